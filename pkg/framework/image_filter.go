@@ -0,0 +1,39 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	internalapi "k8s.io/kubernetes/pkg/kubelet/apis/cri"
+	runtimeapi "k8s.io/kubernetes/pkg/kubelet/apis/cri/runtime/v1alpha2"
+)
+
+// ListDanglingImages lists the images known to the runtime that have no
+// RepoTags. The CRI ImageFilter does not define a "dangling" concept, so
+// this always lists every image and filters client-side. That makes it a
+// safe fallback for runtimes that only implement filtering server-side for
+// the fields the CRI filter does define.
+func ListDanglingImages(c internalapi.ImageManagerService) []*runtimeapi.Image {
+	images := ListImage(c, &runtimeapi.ImageFilter{})
+
+	var dangling []*runtimeapi.Image
+	for _, img := range images {
+		if len(img.RepoTags) == 0 {
+			dangling = append(dangling, img)
+		}
+	}
+	return dangling
+}
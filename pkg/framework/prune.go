@@ -0,0 +1,51 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	internalapi "k8s.io/kubernetes/pkg/kubelet/apis/cri"
+	runtimeapi "k8s.io/kubernetes/pkg/kubelet/apis/cri/runtime/v1alpha2"
+)
+
+// PruneImages removes every dangling image (one with no RepoTags) that is
+// not referenced by any container known to rc, mirroring what a `docker
+// image prune` would do. CRI itself exposes no prune verb, so this is built
+// entirely out of ListImage, ListContainers and RemoveImage, and returns the
+// ids of the images it removed.
+func PruneImages(ic internalapi.ImageManagerService, rc internalapi.RuntimeService) []string {
+	containers, err := rc.ListContainers(&runtimeapi.ContainerFilter{})
+	ExpectNoError(err, "failed to list containers: %v", err)
+
+	inUse := make(map[string]bool)
+	for _, ctr := range containers {
+		inUse[ctr.GetImageRef()] = true
+		if image := ctr.GetImage(); image != nil {
+			inUse[image.GetImage()] = true
+		}
+	}
+
+	var pruned []string
+	for _, img := range ListDanglingImages(ic) {
+		if inUse[img.Id] {
+			continue
+		}
+		err := ic.RemoveImage(&runtimeapi.ImageSpec{Image: img.Id})
+		ExpectNoError(err, "failed to remove dangling image %q: %v", img.Id, err)
+		pruned = append(pruned, img.Id)
+	}
+	return pruned
+}
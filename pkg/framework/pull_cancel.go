@@ -0,0 +1,64 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"time"
+
+	internalapi "k8s.io/kubernetes/pkg/kubelet/apis/cri"
+	runtimeapi "k8s.io/kubernetes/pkg/kubelet/apis/cri/runtime/v1alpha2"
+
+	"github.com/onsi/ginkgo"
+)
+
+// pullCancelGracePeriod bounds how long PullImageWithContext waits for an
+// in-flight pull to unwind after ctx is canceled.
+const pullCancelGracePeriod = 30 * time.Second
+
+// PullImageWithContext starts pulling image in a background goroutine and
+// blocks until either the pull finishes or ctx is canceled. If ctx is
+// canceled first, it issues RemoveImage for the in-flight reference and
+// waits, up to a bounded grace period, for the pull goroutine to return
+// before returning itself. Callers should follow up
+// with ImageStatus to observe whether the pull was actually interrupted.
+func PullImageWithContext(ic internalapi.ImageManagerService, ctx context.Context, image string, podConfig *runtimeapi.PodSandboxConfig) {
+	imageSpec := &runtimeapi.ImageSpec{Image: image}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer ginkgo.GinkgoRecover()
+		// An error here is expected whenever the pull is interrupted by the
+		// RemoveImage call below, so it is intentionally not asserted.
+		ic.PullImage(imageSpec, nil, podConfig)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	err := ic.RemoveImage(imageSpec)
+	ExpectNoError(err, "failed to remove in-flight image %q: %v", image, err)
+
+	select {
+	case <-done:
+	case <-time.After(pullCancelGracePeriod):
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import "flag"
+
+// TestContextType holds the options that configure how the conformance
+// suite behaves against a given CRI runtime. Some CRI behaviors are
+// intentionally left unspecified by the API and runtimes are allowed to
+// pick either of a small number of documented alternatives; those choices
+// are captured here so the suite can assert the one the runtime under test
+// has committed to instead of guessing.
+type TestContextType struct {
+	// IsLcow indicates the test is being run against a Linux Containers
+	// On Windows runtime.
+	IsLcow bool
+
+	// RemoveImageByIDErrors documents whether RemoveImage with the image ID
+	// as the reference is expected to fail when the image still has more
+	// than one RepoTag. When false (the default) the runtime is expected to
+	// remove the image and all of its tags in that situation. Set with
+	// -remove-image-by-id-errors.
+	RemoveImageByIDErrors bool
+
+	// CrossPlatformPull enables tests that pull a manifest list for a
+	// platform other than the one the runtime is running on. It is off by
+	// default because most runtimes only ever pull the native platform. Set
+	// with -cross-platform-pull.
+	CrossPlatformPull bool
+}
+
+// TestContext holds the global test context used by the conformance suite.
+var TestContext = &TestContextType{}
+
+func init() {
+	flag.BoolVar(&TestContext.RemoveImageByIDErrors, "remove-image-by-id-errors", false,
+		"If set, RemoveImage by image ID is expected to error while the image still has more than one RepoTag, instead of removing the image and all of its tags.")
+	flag.BoolVar(&TestContext.CrossPlatformPull, "cross-platform-pull", false,
+		"If set, run tests that pull a manifest list for a non-native platform via ImageSpec.Annotations.")
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package validate
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"sort"
@@ -31,6 +32,21 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// testImageSharedPrefixTag and testImageWithSharedPrefix are published
+// fixtures whose short names share a prefix with testImageShortName. They
+// exist to make sure image reference resolution matches repository
+// boundaries and never falls back to string-prefix matching.
+const (
+	testImageShortName       = "busybox"
+	testImageSharedPrefixTag = "mybusybox:latest"
+)
+
+var testImageWithSharedPrefix = testImageSharedPrefixTag
+
+// pullCancelTestImage is a slow enough pull to reliably still be running at
+// the 500ms mark used by the cancellation test below.
+var pullCancelTestImage = "wordpress"
+
 var _ = framework.KubeDescribe("Image Manager", func() {
 	f := framework.NewDefaultCRIFramework()
 
@@ -168,6 +184,190 @@ var _ = framework.KubeDescribe("Image Manager", func() {
 			}
 		}
 	})
+
+	It("removing an image by tag should only untag it, removing by ID should remove all tags [Conformance]", func() {
+		// Make sure test image does not exist.
+		removeImageList(c, testDifferentTagSameImageList)
+		ids := pullImageList(c, testDifferentTagSameImageList, testImagePodSandbox)
+		ids = removeDuplicates(ids)
+		Expect(len(ids)).To(Equal(1), "Only 1 image id should be returned")
+		imageID := ids[0]
+
+		removedTag := testDifferentTagSameImageList[0]
+		remainingTags := testDifferentTagSameImageList[1:]
+
+		By("Remove image by tag : " + removedTag)
+		Expect(c.RemoveImage(&runtimeapi.ImageSpec{Image: removedTag})).To(Succeed())
+
+		By("Check the removed tag no longer resolves")
+		Expect(framework.ImageStatus(c, removedTag)).To(BeNil(), "removed tag should no longer resolve")
+
+		By("Check the remaining tags still resolve to the same image")
+		for _, tag := range remainingTags {
+			status := framework.ImageStatus(c, tag)
+			Expect(status).NotTo(BeNil(), "remaining tag %q should still resolve", tag)
+			Expect(status.Id).To(Equal(imageID), "image id should not change after untagging")
+		}
+
+		By("Remove image by ID : " + imageID)
+		Expect(c.RemoveImage(&runtimeapi.ImageSpec{Image: imageID})).To(Succeed())
+
+		By("Check all remaining tags are gone")
+		for _, tag := range remainingTags {
+			Expect(framework.ImageStatus(c, tag)).To(BeNil(), "tag %q should be gone after removing by id", tag)
+		}
+	})
+
+	It("removing an image by ID while multiple tags exist should behave consistently [Conformance]", func() {
+		// Make sure test image does not exist.
+		removeImageList(c, testDifferentTagSameImageList)
+		ids := pullImageList(c, testDifferentTagSameImageList, testImagePodSandbox)
+		ids = removeDuplicates(ids)
+		Expect(len(ids)).To(Equal(1), "Only 1 image id should be returned")
+		imageID := ids[0]
+
+		By("Remove image by ID while multiple tags are still present : " + imageID)
+		err := c.RemoveImage(&runtimeapi.ImageSpec{Image: imageID})
+
+		if framework.TestContext.RemoveImageByIDErrors {
+			Expect(err).To(HaveOccurred(), "RemoveImage by ID with multiple tags present should error per TestContext.RemoveImageByIDErrors")
+			removeImageList(c, testDifferentTagSameImageList)
+			return
+		}
+
+		Expect(err).NotTo(HaveOccurred(), "RemoveImage by ID with multiple tags present should succeed per TestContext.RemoveImageByIDErrors")
+		for _, tag := range testDifferentTagSameImageList {
+			Expect(framework.ImageStatus(c, tag)).To(BeNil(), "tag %q should be gone after removing by id", tag)
+		}
+	})
+
+	It("ImageStatus should resolve short names at repository boundaries, not by prefix [Conformance]", func() {
+		// Make sure test images do not exist before testing.
+		removeImage(c, testImageShortName)
+		removeImage(c, testImageWithSharedPrefix)
+
+		framework.PullPublicImage(c, testImageShortName, testImagePodSandbox)
+		defer removeImage(c, testImageShortName)
+		framework.PullPublicImage(c, testImageWithSharedPrefix, testImagePodSandbox)
+		defer removeImage(c, testImageWithSharedPrefix)
+
+		By("Check that " + testImageShortName + " only resolves to its own image")
+		status := framework.ImageStatus(c, testImageShortName)
+		Expect(status).NotTo(BeNil())
+		Expect(status.RepoTags).To(ContainElement(testImageShortName + ":latest"))
+		Expect(status.RepoTags).NotTo(ContainElement(testImageWithSharedPrefix))
+
+		By("Check that " + testImageWithSharedPrefix + " only resolves to its own image")
+		sharedStatus := framework.ImageStatus(c, testImageWithSharedPrefix)
+		Expect(sharedStatus).NotTo(BeNil())
+		Expect(sharedStatus.RepoTags).To(ContainElement(testImageWithSharedPrefix))
+		Expect(sharedStatus.Id).NotTo(Equal(status.Id))
+	})
+
+	It("ImageStatus should not resolve a :none tagged reference to a locally stored untagged copy [Conformance]", func() {
+		// Make sure test image does not exist before testing.
+		removeImage(c, testImageShortName)
+
+		framework.PullPublicImage(c, testImageShortName, testImagePodSandbox)
+		defer removeImage(c, testImageShortName)
+
+		Expect(framework.ImageStatus(c, testImageShortName+":none")).To(BeNil(),
+			"a :none tagged reference should never resolve to a locally stored untagged copy")
+	})
+
+	It("listImage should filter by exact reference [Conformance] [Optional]", func() {
+		removeImage(c, testImageShortName)
+		framework.PullPublicImage(c, testImageShortName, testImagePodSandbox)
+		defer removeImage(c, testImageShortName)
+
+		images := framework.ListImage(c, &runtimeapi.ImageFilter{
+			Image: &runtimeapi.ImageSpec{Image: testImageShortName + ":latest"},
+		})
+		Expect(len(images)).To(Equal(1), "filtering by exact reference should return exactly one image")
+		Expect(images[0].RepoTags).To(ContainElement(testImageShortName + ":latest"))
+	})
+
+	It("listImage should filter by digest reference [Conformance] [Optional]", func() {
+		removeImage(c, testImageWithDigest)
+		framework.PullPublicImage(c, testImageWithDigest, testImagePodSandbox)
+		defer removeImage(c, testImageWithDigest)
+
+		images := framework.ListImage(c, &runtimeapi.ImageFilter{
+			Image: &runtimeapi.ImageSpec{Image: testImageWithDigest},
+		})
+		Expect(len(images)).To(Equal(1), "filtering by digest should return exactly one image")
+		Expect(images[0].RepoDigests).To(ContainElement(testImageWithDigest))
+	})
+
+	It("listImage should find dangling images [Conformance]", func() {
+		// Make sure test image does not exist.
+		removeImageList(c, testDifferentTagSameImageList)
+		ids := pullImageList(c, testDifferentTagSameImageList, testImagePodSandbox)
+		ids = removeDuplicates(ids)
+		Expect(len(ids)).To(Equal(1))
+		imageID := ids[0]
+		defer removeImage(c, imageID)
+
+		By("Untag every RepoTag, leaving a dangling image behind")
+		for _, tag := range testDifferentTagSameImageList {
+			Expect(c.RemoveImage(&runtimeapi.ImageSpec{Image: tag})).To(Succeed())
+		}
+
+		danglingImages := framework.ListDanglingImages(c)
+		var found bool
+		for _, img := range danglingImages {
+			if img.Id == imageID {
+				found = true
+				Expect(img.RepoTags).To(BeEmpty(), "a dangling image should have no RepoTags")
+			}
+		}
+		Expect(found).To(BeTrue(), "the untagged image should show up as dangling")
+	})
+
+	It("should prune dangling images while preserving images backing running containers [Conformance]", func() {
+		rc := f.CRIClient.CRIRuntimeClient
+
+		By("Pull an image and start a container that uses it")
+		removeImage(c, testImageShortName)
+		inUseID := framework.PullPublicImage(c, testImageShortName, testImagePodSandbox)
+
+		podID, podConfig := framework.CreatePodSandboxForContainer(rc)
+		defer func() {
+			Expect(rc.StopPodSandbox(podID)).To(Succeed())
+			Expect(rc.RemovePodSandbox(podID)).To(Succeed())
+		}()
+		containerConfig := &runtimeapi.ContainerConfig{
+			Metadata: framework.BuildContainerMetadata(testImageShortName, framework.DefaultAttempt),
+			Image:    &runtimeapi.ImageSpec{Image: testImageShortName},
+			Command:  []string{"sh", "-c", "sleep 1000"},
+		}
+		containerID := framework.CreateContainer(rc, c, containerConfig, podID, podConfig)
+		Expect(rc.StartContainer(containerID)).To(Succeed())
+		defer func() {
+			Expect(rc.StopContainer(containerID, 10)).To(Succeed())
+			Expect(rc.RemoveContainer(containerID)).To(Succeed())
+		}()
+
+		By("Untag the in-use image so it becomes a dangling candidate while the container is still running")
+		Expect(c.RemoveImage(&runtimeapi.ImageSpec{Image: testImageShortName + ":latest"})).To(Succeed())
+
+		By("Pull a second image under multiple tags, then untag all of them so it becomes dangling")
+		removeImageList(c, testDifferentTagSameImageList)
+		ids := pullImageList(c, testDifferentTagSameImageList, testImagePodSandbox)
+		ids = removeDuplicates(ids)
+		Expect(len(ids)).To(Equal(1))
+		danglingID := ids[0]
+		for _, tag := range testDifferentTagSameImageList {
+			Expect(c.RemoveImage(&runtimeapi.ImageSpec{Image: tag})).To(Succeed())
+		}
+
+		By("Prune dangling images")
+		pruned := framework.PruneImages(c, rc)
+
+		Expect(pruned).To(ConsistOf(danglingID), "only the dangling, unreferenced image should be pruned")
+		Expect(framework.ImageStatus(c, inUseID)).NotTo(BeNil(), "the dangling image backing a running container must survive pruning")
+		Expect(framework.ImageStatus(c, danglingID)).To(BeNil(), "the pruned image should no longer exist")
+	})
 })
 
 var _ = framework.KubeOptionalDescribe("Image Manager", func() {
@@ -350,6 +550,41 @@ var _ = framework.KubeOptionalDescribe("Image Manager", func() {
 		})
 	})
 
+	Context("cancel an in-flight pull [Slow]", func() {
+		AfterEach(func() {
+			ic.RemoveImage(&runtimeapi.ImageSpec{Image: pullCancelTestImage})
+		})
+
+		It("should clean up after a canceled pull and allow a clean re-pull [Optional]", func() {
+			ic.RemoveImage(&runtimeapi.ImageSpec{Image: pullCancelTestImage})
+
+			By("Start pulling " + pullCancelTestImage + " and cancel it after 500ms")
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(500*time.Millisecond, cancel)
+			framework.PullImageWithContext(ic, ctx, pullCancelTestImage, testImagePodSandbox)
+
+			Eventually(func() *runtimeapi.Image {
+				return framework.ImageStatus(ic, pullCancelTestImage)
+			}, time.Minute, time.Second).Should(BeNil(), "the canceled pull should leave no trace of the image")
+
+			By("Pull the same image again and make sure it completes cleanly")
+			framework.PullPublicImage(ic, pullCancelTestImage, testImagePodSandbox)
+			status := framework.ImageStatus(ic, pullCancelTestImage)
+			Expect(status).NotTo(BeNil())
+
+			By("Pull once more on a clean sandbox as a baseline and compare sizes")
+			removeImage(ic, pullCancelTestImage)
+			podID, podConfig := framework.CreatePodSandboxForContainer(rc)
+			defer func() {
+				Expect(rc.StopPodSandbox(podID)).To(Succeed())
+				Expect(rc.RemovePodSandbox(podID)).To(Succeed())
+			}()
+			framework.PullPublicImage(ic, pullCancelTestImage, podConfig)
+			baseline := framework.ImageStatus(ic, pullCancelTestImage)
+			Expect(baseline).NotTo(BeNil())
+			Expect(status.Size_).To(Equal(baseline.Size_), "a re-pull after cancellation should not leave partial layers behind")
+		})
+	})
 })
 
 // testRemoveImage removes the image name imageName and check if it successes.
@@ -0,0 +1,108 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"runtime"
+
+	"github.com/kubernetes-sigs/cri-tools/pkg/framework"
+	internalapi "k8s.io/kubernetes/pkg/kubelet/apis/cri"
+	runtimeapi "k8s.io/kubernetes/pkg/kubelet/apis/cri/runtime/v1alpha2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	// testImageManifestListDigest is a published fixture, referenced by the
+	// digest of the manifest list (image index) itself, covering multiple
+	// platforms. This is the reference the image is requested by.
+	testImageManifestListDigest = "gcr.io/cri-tools-test/manifest-list@sha256:1111111111111111111111111111111111111111111111111111111111111111"
+
+	// testImageManifestListPlatformDigest is the digest of the
+	// platform-specific manifest for runtime.GOARCH contained in the index
+	// above.
+	testImageManifestListPlatformDigest = "gcr.io/cri-tools-test/manifest-list@sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// testCrossPlatformArch is a non-native architecture, used to make sure
+// runtimes that support ImageSpec.Annotations platform hints actually honor
+// them instead of always pulling the native manifest.
+var testCrossPlatformArch = func() string {
+	if runtime.GOARCH == "arm64" {
+		return "amd64"
+	}
+	return "arm64"
+}()
+
+var _ = framework.KubeDescribe("Image Manager - Manifest Lists", func() {
+	f := framework.NewDefaultCRIFramework()
+
+	var c internalapi.ImageManagerService
+
+	BeforeEach(func() {
+		c = f.CRIClient.CRIImageClient
+	})
+
+	It("should pull the platform-appropriate manifest from a manifest list [Conformance]", func() {
+		// Make sure test image does not exist before testing.
+		removeImage(c, testImageManifestListDigest)
+
+		framework.PullPublicImage(c, testImageManifestListDigest, testImagePodSandbox)
+		defer removeImage(c, testImageManifestListDigest)
+
+		status := framework.ImageStatus(c, testImageManifestListDigest)
+		Expect(status).NotTo(BeNil())
+
+		By("Check RepoDigests contains the digest the image was requested by")
+		Expect(status.RepoDigests).To(ContainElement(testImageManifestListDigest))
+
+		By("Check the image id corresponds to the platform manifest, not the index digest, by pulling the platform-specific digest directly and comparing ids")
+		framework.PullPublicImage(c, testImageManifestListPlatformDigest, testImagePodSandbox)
+		platformStatus := framework.ImageStatus(c, testImageManifestListPlatformDigest)
+		Expect(platformStatus).NotTo(BeNil())
+		Expect(platformStatus.Id).To(Equal(status.Id),
+			"image id should correspond to the %s manifest, not the index digest", runtime.GOARCH)
+	})
+
+	It("should honor a non-native platform hint in ImageSpec.Annotations [Conformance] [Optional]", func() {
+		if !framework.TestContext.CrossPlatformPull {
+			Skip("cross-platform pull is disabled, set TestContext.CrossPlatformPull to enable")
+		}
+
+		removeImage(c, testImageManifestListDigest)
+
+		nativeID := framework.PullPublicImage(c, testImageManifestListDigest, testImagePodSandbox)
+		defer removeImage(c, testImageManifestListDigest)
+
+		crossSpec := &runtimeapi.ImageSpec{
+			Image: testImageManifestListDigest,
+			Annotations: map[string]string{
+				"io.cri-tools.platform.arch": testCrossPlatformArch,
+			},
+		}
+
+		By("Pull the same manifest list again, requesting a non-native platform")
+		_, err := c.PullImage(crossSpec, nil, testImagePodSandbox)
+		framework.ExpectNoError(err, "failed to pull image with cross-platform hint: %v", err)
+
+		crossStatus, err := c.ImageStatus(crossSpec)
+		framework.ExpectNoError(err, "failed to get image status: %v", err)
+		Expect(crossStatus).NotTo(BeNil())
+		Expect(crossStatus.Id).NotTo(Equal(nativeID), "pulling a non-native platform hint should not reuse the native manifest")
+	})
+})